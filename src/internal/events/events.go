@@ -0,0 +1,58 @@
+package events
+
+import "context"
+
+// Eventer emits operation lifecycle events to whatever telemetry sink a
+// caller has wired up (analytics, audit log, a local no-op in tests).
+type Eventer interface {
+	Event(ctx context.Context, name string, data map[string]any)
+}
+
+// Event names.
+const (
+	// BackupStart fires once a BackupOperation.Run has a BackupID and is
+	// about to start producing collections.
+	BackupStart = "backup.start"
+	// BackupEnd fires once Run has written the backup model. A Run that
+	// fails, or stops before a backup model is written, emits no BackupEnd.
+	BackupEnd = "backup.end"
+	// BackupEncrypted fires once a backup's data-encryption key has been
+	// wrapped and its cipher metadata persisted.
+	BackupEncrypted = "backup.encrypted"
+	// BackupFallbackFull fires when an incremental backup's base backup turns
+	// out to be unusable as an anchor and Run falls back to a full backup.
+	BackupFallbackFull = "backup.fallback_full"
+	// BackupProgress fires periodically while a rate-limited backup is in
+	// flight, reporting the observed throughput.
+	BackupProgress = "backup.progress"
+	// HookStart fires just before a BackupHook phase runs.
+	HookStart = "hook.start"
+	// HookEnd fires just after a BackupHook phase runs, regardless of
+	// whether it returned an error.
+	HookEnd = "hook.end"
+)
+
+// Event data keys.
+const (
+	BackupID  = "backup_id"
+	StartTime = "start_time"
+	EndTime   = "end_time"
+	Duration  = "duration"
+	Service   = "service"
+	Status    = "status"
+	// DataStored is the number of bytes a backup uploaded.
+	DataStored = "data_stored"
+	// Resources is the number of resource owners a backup covered.
+	Resources = "resources"
+	// KeyID identifies which master key wrapped a backup's data-encryption
+	// key.
+	KeyID = "key_id"
+	// Reason carries a human-readable explanation for an event that reports
+	// a non-fatal deviation, such as BackupFallbackFull.
+	Reason = "reason"
+	// BytesPerSec is the observed throughput reported with BackupProgress.
+	BytesPerSec = "bytes_per_sec"
+	// HookName identifies which hook phase a HookStart/HookEnd event belongs
+	// to (e.g. "BeforeProduce").
+	HookName = "hook_name"
+)
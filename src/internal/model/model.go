@@ -0,0 +1,36 @@
+package model
+
+// StableID identifies a model instance across its lifetime, independent of
+// whatever storage-specific key the store.Wrapper happens to assign it.
+// Callers that need to look a model back up later (a backup, a checkpoint,
+// a cipher record) store it under its own StableID so the lookup doesn't
+// depend on remembering an opaque store key.
+type StableID string
+
+// BaseModel is embedded by every model persisted through store.Wrapper. ID
+// is what a schema's Put/Get key on; everything else is tracked by the
+// store itself.
+type BaseModel struct {
+	ID StableID `json:"id"`
+}
+
+// Schema identifies which kind of model a store.Wrapper call is operating
+// on.
+type Schema string
+
+const (
+	// BackupSchema is the schema for a completed (or partially completed,
+	// cancelled) backup.Backup record.
+	BackupSchema Schema = "backup"
+	// BackupDetailsSchema is the schema for the per-item details.Details
+	// belonging to a backup.
+	BackupDetailsSchema Schema = "backupDetails"
+	// CheckpointSchema is the schema for an in-progress backup's
+	// CheckpointMetadata, letting a crashed or cancelled BackupOperation.Run
+	// be resumed instead of redone from scratch.
+	CheckpointSchema Schema = "checkpoint"
+	// CipherMetadataSchema is the schema for a backup's wrapped
+	// data-encryption key and item nonces, used to restore a backup taken
+	// with client-side envelope encryption enabled.
+	CipherMetadataSchema Schema = "cipherMetadata"
+)
@@ -2,11 +2,13 @@ package operations
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
 
 	"github.com/alcionai/corso/src/internal/connector"
 	"github.com/alcionai/corso/src/internal/connector/support"
@@ -35,6 +37,32 @@ type BackupOperation struct {
 	Version   string             `json:"version"`
 
 	account account.Account
+
+	// checkpoint tracks resumable progress for this operation's Run call. Its
+	// runner is nil until Run has connected to M365. It's boxed behind a
+	// pointer, rather than held directly, so BackupOperation stays safe to
+	// copy by value (as NewBackupOperation/StartBackupOperation do) while
+	// every copy still shares the same mutex -- BackupHandle.Pause reads the
+	// runner from a different goroutine than the one Run assigns it from.
+	checkpoint *checkpointBox
+
+	// cipher and dek implement client-side envelope encryption. dek is
+	// generated fresh per backup and is nil unless cipher.Enabled.
+	cipher control.CipherConfig
+	dek    control.Sensitive
+
+	// baseBackupID anchors this operation on a prior backup for an
+	// incremental run. It's empty for a full backup.
+	baseBackupID model.StableID
+
+	// rate limiting and concurrency knobs, copied from control.Options at
+	// construction time.
+	rateLimitBytesPerSec uint64
+	collectionWorkers    int
+	itemFetchWorkers     int
+
+	// hooks run around the backup lifecycle. Populated via WithHooks.
+	hooks []BackupHook
 }
 
 // BackupResults aggregate the details of the result of the operation.
@@ -43,6 +71,35 @@ type BackupResults struct {
 	stats.ReadWrites
 	stats.StartAndEndTime
 	BackupID model.StableID `json:"backupID"`
+
+	// ParentBackupID is empty for a full backup and set to the anchoring
+	// backup's ID for an incremental one.
+	ParentBackupID model.StableID `json:"parentBackupID,omitempty"`
+
+	// ItemsAdded counts items new to this backup. For a full backup that's
+	// every item backed up; for an incremental backup, the connector's delta
+	// fetch doesn't distinguish new items from changed ones, so incremental
+	// runs leave this at zero and report the whole live count under
+	// ItemsModified instead.
+	ItemsAdded int `json:"itemsAdded,omitempty"`
+
+	// ItemsModified counts non-deleted items backed up by an incremental run.
+	// It's always zero for a full backup.
+	ItemsModified int `json:"itemsModified,omitempty"`
+
+	// ItemsDeleted counts items the connector reported as tombstoned in this
+	// run's collections.
+	ItemsDeleted int `json:"itemsDeleted,omitempty"`
+
+	// BytesPerSec is the average upload throughput across the whole run
+	// (BytesUploaded over wall-clock duration), not a per-worker figure --
+	// CollectionWorkers/ItemFetchWorkers fan-out happens inside the
+	// connector, which this package has no visibility into per-worker. The
+	// duration covers the whole Run, not just the upload, so this differs
+	// from the per-interval read rate events.BackupProgress reports while a
+	// rate-limited run is in flight. It's zero unless the upload to kopia
+	// finished.
+	BytesPerSec float64 `json:"bytesPerSec,omitempty"`
 }
 
 // NewBackupOperation constructs and validates a backup operation.
@@ -54,17 +111,37 @@ func NewBackupOperation(
 	acct account.Account,
 	selector selectors.Selector,
 	bus events.Eventer,
+	options ...Option,
 ) (BackupOperation, error) {
 	op := BackupOperation{
-		operation: newOperation(opts, bus, kw, sw),
-		Selectors: selector,
-		Version:   "v0",
-		account:   acct,
+		operation:            newOperation(opts, bus, kw, sw),
+		Selectors:            selector,
+		Version:              "v0",
+		account:              acct,
+		checkpoint:           &checkpointBox{},
+		cipher:               opts.Cipher,
+		rateLimitBytesPerSec: opts.RateLimitBytesPerSec,
+		collectionWorkers:    opts.CollectionWorkers,
+		itemFetchWorkers:     opts.ItemFetchWorkers,
 	}
+
+	for _, applyOpt := range options {
+		applyOpt(&op)
+	}
+
 	if err := op.validate(); err != nil {
 		return BackupOperation{}, err
 	}
 
+	if op.cipher.Enabled {
+		dek, err := generateDataEncryptionKey()
+		if err != nil {
+			return BackupOperation{}, errors.Wrap(err, "generating data encryption key")
+		}
+
+		op.dek = dek
+	}
+
 	return op, nil
 }
 
@@ -72,6 +149,137 @@ func (op BackupOperation) validate() error {
 	return op.operation.validate()
 }
 
+// checkpointBox holds the operation's checkpoint runner and the means to
+// pause it, behind a mutex. BackupOperation stores a pointer to one rather
+// than holding these directly, so the mutex is shared across every copy of
+// BackupOperation instead of being duplicated by one.
+type checkpointBox struct {
+	mu sync.Mutex
+	cr *CheckpointRunner
+	// cancelWork, once Run has set it, stops produceBackupDataCollections and
+	// consumeBackupDataCollections from doing any more new work. It's nil
+	// until Run reaches that point.
+	cancelWork context.CancelFunc
+	paused     bool
+}
+
+// getCheckpoint returns the operation's current checkpoint runner, or nil if
+// Run hasn't connected to M365 yet. Safe to call concurrently with Run.
+func (op *BackupOperation) getCheckpoint() *CheckpointRunner {
+	op.checkpoint.mu.Lock()
+	defer op.checkpoint.mu.Unlock()
+
+	return op.checkpoint.cr
+}
+
+// setCheckpoint installs the operation's checkpoint runner. Safe to call
+// concurrently with getCheckpoint.
+func (op *BackupOperation) setCheckpoint(cr *CheckpointRunner) {
+	op.checkpoint.mu.Lock()
+	op.checkpoint.cr = cr
+	op.checkpoint.mu.Unlock()
+}
+
+// setCancelWork installs the func that stops Run from starting any more
+// collection work. Safe to call concurrently with pause.
+func (op *BackupOperation) setCancelWork(cancel context.CancelFunc) {
+	op.checkpoint.mu.Lock()
+	op.checkpoint.cancelWork = cancel
+	op.checkpoint.mu.Unlock()
+}
+
+// pause marks the operation paused and stops any new collection work Run
+// hasn't already started. Safe to call concurrently with Run.
+func (op *BackupOperation) pause() {
+	op.checkpoint.mu.Lock()
+	op.checkpoint.paused = true
+	cancel := op.checkpoint.cancelWork
+	op.checkpoint.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// isPaused reports whether pause has been called for this operation.
+func (op *BackupOperation) isPaused() bool {
+	op.checkpoint.mu.Lock()
+	defer op.checkpoint.mu.Unlock()
+
+	return op.checkpoint.paused
+}
+
+// ResumeBackupOperation reconstructs a BackupOperation from a checkpoint left
+// behind by a prior, interrupted Run. Run re-enters at the next collection
+// that isn't already recorded in the checkpoint.
+//
+// Rate limiting, concurrency, and the incremental anchor are restored from
+// the checkpoint itself, since they're needed to resume the same way the
+// interrupted attempt was running. Hooks aren't: a BackupHook can't be
+// serialized, so options is applied the same way NewBackupOperation applies
+// it, and a caller that registered hooks on the original Run must pass
+// WithHooks again here to get them back.
+//
+// A checkpoint left by an encrypted backup can't be resumed at all: its
+// data-encryption key lived only in the crashed process's memory and was
+// zeroed on exit, so there's no way to recover it, and generating a new one
+// would mismatch whatever items the interrupted run already flushed into the
+// same kopia snapshot under the old key.
+func ResumeBackupOperation(
+	ctx context.Context,
+	opts control.Options,
+	kw *kopia.Wrapper,
+	sw *store.Wrapper,
+	acct account.Account,
+	backupID model.StableID,
+	bus events.Eventer,
+	options ...Option,
+) (BackupOperation, error) {
+	var meta CheckpointMetadata
+	if err := sw.Get(ctx, model.CheckpointSchema, backupID, &meta); err != nil {
+		return BackupOperation{}, errors.Wrap(err, "loading backup checkpoint")
+	}
+
+	if meta.SelectorsHash != selectorsHash(meta.Selector, acct) {
+		return BackupOperation{}, errors.New("checkpoint does not match selectors or tenant")
+	}
+
+	// a backup model under the same ID means the run that left this
+	// checkpoint completed, and createBackupModels' best-effort delete of the
+	// checkpoint failed afterwards.
+	if err := sw.Get(ctx, model.BackupSchema, backupID, &backup.Backup{}); err == nil {
+		return BackupOperation{}, errors.New("backup already completed; its checkpoint is stale")
+	}
+
+	if meta.CipherEnabled {
+		return BackupOperation{}, errors.New(
+			"cannot resume an encrypted backup: its data encryption key is unrecoverable, run a fresh backup instead")
+	}
+
+	op := BackupOperation{
+		operation:            newOperation(opts, bus, kw, sw),
+		Selectors:            meta.Selector,
+		Version:              "v0",
+		account:              acct,
+		checkpoint:           &checkpointBox{cr: newCheckpointRunner(sw, meta)},
+		baseBackupID:         meta.BaseBackupID,
+		rateLimitBytesPerSec: meta.RateLimitBytesPerSec,
+		collectionWorkers:    meta.CollectionWorkers,
+		itemFetchWorkers:     meta.ItemFetchWorkers,
+	}
+	op.Results.BackupID = meta.BackupID
+
+	for _, applyOpt := range options {
+		applyOpt(&op)
+	}
+
+	if err := op.validate(); err != nil {
+		return BackupOperation{}, err
+	}
+
+	return op, nil
+}
+
 // aggregates stats from the backup.Run().
 // primarily used so that the defer can take in a
 // pointer wrapping the values, while those values
@@ -84,18 +292,32 @@ type backupStats struct {
 	readErr, writeErr error
 }
 
-// Run begins a synchronous backup operation.
+// Run begins a synchronous backup operation. ctx is wrapped in an
+// operation-owned cancel so that every goroutine Run spawns (checkpoint
+// flushing, progress reporting) derives from the same cancellation source.
+// Callers that want to stop an in-flight Run from another goroutine should
+// use StartBackupOperation instead of managing their own context.
 func (op *BackupOperation) Run(ctx context.Context) (err error) {
 	ctx, end := D.Span(ctx, "operations:backup:run")
 	defer end()
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	var (
 		opStats       backupStats
 		backupDetails *details.Details
 		startTime     = time.Now()
 	)
 
-	op.Results.BackupID = model.StableID(uuid.NewString())
+	// the data encryption key only needs to live for the duration of this
+	// Run call; zero it on the way out regardless of outcome.
+	defer op.dek.Zero()
+
+	// a resumed operation already has a BackupID and a checkpoint runner.
+	if len(op.Results.BackupID) == 0 {
+		op.Results.BackupID = model.StableID(uuid.NewString())
+	}
 
 	op.bus.Event(
 		ctx,
@@ -107,17 +329,40 @@ func (op *BackupOperation) Run(ctx context.Context) (err error) {
 		},
 	)
 
-	// persist operation results to the model store on exit
+	// persist operation results to the model store on exit. This must
+	// complete even if ctx was cancelled out from under Run, so it runs
+	// against a detached context that keeps ctx's values but drops its
+	// cancellation -- otherwise a caller's Cancel() would race the store
+	// writes it depends on to record the cancellation cleanly.
 	defer func() {
+		persistCtx := detach(ctx)
+
 		// wait for the progress display to clean up
 		observe.Complete()
 
-		err = op.persistResults(startTime, &opStats)
+		if cr := op.getCheckpoint(); cr != nil {
+			if cpErr := cr.stop(persistCtx); cpErr != nil {
+				logger.Ctx(ctx).With("error", cpErr).Error("stopping checkpoint runner")
+			}
+		}
+
+		err = op.persistResults(ctx, startTime, &opStats)
 		if err != nil {
 			return
 		}
 
-		err = op.createBackupModels(ctx, opStats.k.SnapshotID, backupDetails)
+		if opStats.k == nil || backupDetails == nil {
+			// opStats.k can be non-nil without backupDetails: a cancellation
+			// or pause mid-upload still reports the partial snapshot-in-
+			// progress ID consumeBackupDataCollections captured (recorded on
+			// the checkpoint above already), but consumeBackupDataCollections
+			// only returns backupDetails once it finishes successfully. There
+			// isn't enough here to write a full backup model either way --
+			// the checkpoint is what ResumeBackupOperation anchors on instead.
+			return
+		}
+
+		err = op.createBackupModels(persistCtx, opStats.k.SnapshotID, backupDetails)
 		if err != nil {
 			opStats.writeErr = err
 		}
@@ -129,18 +374,218 @@ func (op *BackupOperation) Run(ctx context.Context) (err error) {
 		return opStats.readErr
 	}
 
-	cs, err := produceBackupDataCollections(ctx, gc, op.Selectors)
+	// From here on, AfterConsume hooks run no matter how Run exits, so
+	// integrators can reliably undo whatever a BeforeProduce/BeforeConsume
+	// hook did (e.g. restart containers that were stopped to quiesce for the
+	// backup). OnError hooks fire once, with whichever error ended the run.
+	// A failed connectToM365 above returns before this defer is registered,
+	// so neither AfterConsume nor OnError hooks run in that case.
+	var hookStats *kopia.BackupStats
+
+	defer func() {
+		hookCtx := detach(ctx)
+
+		if afterErr := op.runAfterConsume(hookCtx, hookStats); afterErr != nil {
+			logger.Ctx(ctx).With("error", afterErr).Error("running after-consume hooks")
+
+			// an AfterConsume failure (e.g. a hook couldn't restart containers
+			// it stopped to quiesce for the backup) has to reach the caller
+			// the same way any other write-phase failure does, not just a log
+			// line -- persistResults copies opStats.writeErr onto
+			// Results.WriteErrors regardless of how the rest of Run went.
+			if opStats.writeErr == nil {
+				opStats.writeErr = afterErr
+			} else {
+				opStats.writeErr = multierror.Append(opStats.writeErr, afterErr)
+			}
+		}
+
+		runErr := err
+		if runErr == nil {
+			runErr = opStats.readErr
+		}
+
+		if runErr == nil {
+			runErr = opStats.writeErr
+		}
+
+		if runErr != nil {
+			op.runOnError(hookCtx, runErr)
+		}
+	}()
+
+	if err := op.runBeforeProduce(ctx); err != nil {
+		opStats.readErr = errors.Wrap(err, "running before-produce hooks")
+		return opStats.readErr
+	}
+
+	var base *baseBackup
+	if len(op.baseBackupID) > 0 {
+		base, err = loadBaseBackup(ctx, op.store, *op)
+		if err != nil {
+			// the parent backup is unusable as an anchor (missing, or
+			// mismatched tenant/service/selector) -- fall back to a full
+			// backup rather than failing outright.
+			op.fallbackToFullBackup(ctx, err)
+			base = nil
+		} else {
+			op.Results.ParentBackupID = op.baseBackupID
+		}
+	}
+
+	cr := op.getCheckpoint()
+	if cr == nil {
+		cr = newCheckpointRunner(op.store, CheckpointMetadata{
+			BackupID:             op.Results.BackupID,
+			Selector:             op.Selectors,
+			SelectorsHash:        selectorsHash(op.Selectors, op.account),
+			Service:              op.Selectors.Service.String(),
+			StartedAt:            startTime,
+			BaseBackupID:         op.baseBackupID,
+			RateLimitBytesPerSec: op.rateLimitBytesPerSec,
+			CollectionWorkers:    op.collectionWorkers,
+			ItemFetchWorkers:     op.itemFetchWorkers,
+			CipherEnabled:        op.cipher.Enabled,
+			CipherKeyID:          op.cipher.KeyID,
+		})
+		op.setCheckpoint(cr)
+	}
+
+	if err := cr.start(ctx); err != nil {
+		opStats.readErr = errors.Wrap(err, "writing backup checkpoint")
+		return opStats.readErr
+	}
+
+	// workCtx, not ctx, gates produceBackupDataCollections and
+	// consumeBackupDataCollections specifically, so a caller's Pause stops
+	// new collection work without cancelling ctx itself -- everything else
+	// (hooks, the checkpoint flush loop, this defer's cleanup) keeps running
+	// against ctx as normal.
+	workCtx, cancelWork := context.WithCancel(ctx)
+	defer cancelWork()
+	op.setCancelWork(cancelWork)
+
+	// a Pause that raced Run's startup, landing before setCancelWork above,
+	// would otherwise be lost -- check now that cancelWork is installed.
+	if op.isPaused() {
+		cancelWork()
+	}
+
+	alreadyDone := cr.completedPaths()
+
+	cs, err := produceBackupDataCollections(workCtx, gc, op.Selectors, base, alreadyDone, op.collectionWorkers, op.itemFetchWorkers)
+	if err != nil && base != nil && isDeltaExpiredErr(err) {
+		// the parent's delta token went stale upstream sometime after
+		// loadBaseBackup validated it as an anchor -- retry once, from
+		// scratch, instead of failing the whole backup over a condition
+		// that's expected to happen occasionally.
+		op.fallbackToFullBackup(ctx, err)
+		base = nil
+
+		cs, err = produceBackupDataCollections(workCtx, gc, op.Selectors, base, alreadyDone, op.collectionWorkers, op.itemFetchWorkers)
+	}
+
 	if err != nil {
 		opStats.readErr = errors.Wrap(err, "retrieving data to backup")
 		return opStats.readErr
 	}
 
-	opStats.k, backupDetails, err = consumeBackupDataCollections(ctx, op.kopia, op.Selectors, cs)
+	if err := op.runAfterProduce(ctx, cs); err != nil {
+		opStats.readErr = errors.Wrap(err, "running after-produce hooks")
+		return opStats.readErr
+	}
+
+	tally := &itemTally{}
+	cs = tallyCollections(tally, cs)
+
+	var itemNonces *nonceRegistry
+	if op.cipher.Enabled {
+		cs, itemNonces = encryptCollections(op.dek, cs)
+	}
+
+	var track *throughputTracker
+	if op.rateLimitBytesPerSec > 0 {
+		track = &throughputTracker{}
+		limiter := rate.NewLimiter(rate.Limit(op.rateLimitBytesPerSec), int(op.rateLimitBytesPerSec))
+		cs = rateLimitCollections(limiter, track, cs)
+
+		progressCtx, cancelProgress := context.WithCancel(ctx)
+		defer cancelProgress()
+
+		go reportProgress(progressCtx, op.bus, op.Results.BackupID, track)
+	}
+
+	if err := op.runBeforeConsume(ctx); err != nil {
+		opStats.writeErr = errors.Wrap(err, "running before-consume hooks")
+		return opStats.writeErr
+	}
+
+	// an incremental backup's anchor snapshot takes priority; absent that,
+	// fall back to this same operation's own in-progress snapshot from a
+	// prior, interrupted Run, so kopia only has to persist what wasn't
+	// already flushed before the resume.
+	var baseSnapshotID string
+	if base != nil {
+		baseSnapshotID = base.model.SnapshotID
+	} else {
+		baseSnapshotID = cr.snapshotID()
+	}
+
+	opStats.k, backupDetails, err = consumeBackupDataCollections(workCtx, op.kopia, op.Selectors, baseSnapshotID, cs)
+
+	// a cancellation or pause mid-upload is the most likely way this call
+	// fails, and kopia still reports whatever snapshot-in-progress ID it had
+	// flushed before stopping -- record it now, even on error, so a resumed
+	// Run anchors its own upload on it instead of restarting the snapshot
+	// from scratch. This has to happen before the error check below, since
+	// that returns out of Run entirely.
+	if opStats.k != nil && opStats.k.SnapshotID != "" {
+		if serr := cr.setSnapshotID(ctx, opStats.k.SnapshotID); serr != nil {
+			logger.Ctx(ctx).With("error", serr).Error("recording checkpoint snapshot id")
+		}
+	}
+
 	if err != nil {
 		opStats.writeErr = errors.Wrap(err, "backing up service data")
 		return opStats.writeErr
 	}
 
+	hookStats = opStats.k
+
+	if op.cipher.Enabled {
+		wrapped, err := wrapDataEncryptionKey(op.cipher, op.dek)
+		if err != nil {
+			opStats.writeErr = errors.Wrap(err, "wrapping data encryption key")
+			return opStats.writeErr
+		}
+
+		err = persistCipherMetadata(ctx, op.store, cipherMetadata{
+			BackupID:   op.Results.BackupID,
+			KeyID:      op.cipher.KeyID,
+			WrappedDEK: wrapped,
+			ItemNonces: itemNonces.snapshot(),
+		})
+		if err != nil {
+			opStats.writeErr = errors.Wrap(err, "persisting cipher metadata")
+			return opStats.writeErr
+		}
+
+		op.bus.Event(
+			ctx,
+			events.BackupEncrypted,
+			map[string]any{
+				events.BackupID: op.Results.BackupID,
+				events.KeyID:    op.cipher.KeyID,
+			},
+		)
+	}
+
+	for _, c := range cs {
+		if err := cr.recordCollection(ctx, c.FullPath().ResourceOwner(), c.FullPath().String()); err != nil {
+			logger.Ctx(ctx).With("error", err).Error("recording collection checkpoint")
+		}
+	}
+
 	logger.Ctx(ctx).Debugf(
 		"Backed up %d directories and %d files",
 		opStats.k.TotalDirectoryCount, opStats.k.TotalFileCount,
@@ -151,14 +596,48 @@ func (op *BackupOperation) Run(ctx context.Context) (err error) {
 	opStats.started = true
 	opStats.gc = gc.AwaitStatus()
 
+	op.Results.ItemsDeleted = int(tally.deleted)
+
+	// the connector's delta fetch doesn't tell us which live items are new
+	// versus changed, only that they came back in the delta -- so a fresh
+	// (non-incremental) backup's live items are all genuinely new, but an
+	// incremental backup's live items are counted as modified even though
+	// some of them are additions. Splitting that further needs the connector
+	// to surface it, which it doesn't yet.
+	if base == nil {
+		op.Results.ItemsAdded = int(tally.live)
+	} else {
+		op.Results.ItemsModified = int(tally.live)
+	}
+
 	return err
 }
 
-// calls the producer to generate collections of data to backup
+// calls the producer to generate collections of data to backup. When base is
+// non-nil, this is an incremental backup: the GraphConnector is given the
+// prior backup's details and issues Graph delta queries scoped to items
+// changed since it, instead of enumerating everything from scratch.
+//
+// collectionWorkers and itemFetchWorkers are only threaded through to the
+// connector's FetchOptions here; the fan-out itself -- concurrently
+// enumerating collections, concurrently fetching items within one -- happens
+// inside gc.DataCollections, not in this function. This package has no
+// lower-level call into the connector to fan out over, so there's nothing
+// for a semaphore to bound at this layer.
+//
+// alreadyDone is the set of collection paths a resumed Run's checkpoint
+// already recorded as fully uploaded in a prior, interrupted attempt; those
+// collections are dropped from the result so Run doesn't re-fetch and
+// re-upload work that already landed in the snapshot-in-progress. It's empty
+// for a fresh (non-resumed) Run.
 func produceBackupDataCollections(
 	ctx context.Context,
 	gc *connector.GraphConnector,
 	sel selectors.Selector,
+	base *baseBackup,
+	alreadyDone map[string]bool,
+	collectionWorkers int,
+	itemFetchWorkers int,
 ) ([]data.Collection, error) {
 	complete, closer := observe.MessageWithCompletion("Discovering items to backup:")
 	defer func() {
@@ -167,19 +646,54 @@ func produceBackupDataCollections(
 		closer()
 	}()
 
-	cs, err := gc.DataCollections(ctx, sel, nil)
+	var prevDeets *details.Details
+	if base != nil {
+		prevDeets = base.deets
+	}
+
+	// zero means "use the connector's default"; only override when the
+	// caller explicitly configured a value via control.Options.
+	opts := connector.FetchOptions{
+		CollectionWorkers: collectionWorkers,
+		ItemFetchWorkers:  itemFetchWorkers,
+	}
+
+	cs, err := gc.DataCollections(ctx, sel, prevDeets, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return cs, nil
+	if len(alreadyDone) == 0 {
+		return cs, nil
+	}
+
+	remaining := make([]data.Collection, 0, len(cs))
+
+	for _, c := range cs {
+		if alreadyDone[c.FullPath().String()] {
+			continue
+		}
+
+		remaining = append(remaining, c)
+	}
+
+	return remaining, nil
 }
 
-// calls kopia to backup the collections of data
+// calls kopia to backup the collections of data. baseSnapshotID, when
+// non-empty, is reused as the parent snapshot so kopia only has to persist
+// what changed since it.
+//
+// kstats is returned even when err is non-nil: a cancellation or pause
+// landing mid-upload is the single most likely way for this call to fail,
+// and kopia still reports whatever snapshot-in-progress ID and partial
+// counts it flushed before stopping. Run needs that to record a checkpoint
+// so ResumeBackupOperation picks up from there instead of starting over.
 func consumeBackupDataCollections(
 	ctx context.Context,
 	kw *kopia.Wrapper,
 	sel selectors.Selector,
+	baseSnapshotID string,
 	cs []data.Collection,
 ) (*kopia.BackupStats, *details.Details, error) {
 	complete, closer := observe.MessageWithCompletion("Backing up data:")
@@ -189,9 +703,9 @@ func consumeBackupDataCollections(
 		closer()
 	}()
 
-	kstats, deets, err := kw.BackupCollections(ctx, nil, cs, sel.PathService())
+	kstats, deets, err := kw.BackupCollections(ctx, baseSnapshotID, cs, sel.PathService())
 	if err != nil {
-		return nil, nil, err
+		return kstats, nil, err
 	}
 
 	return kstats, deets, nil
@@ -200,23 +714,44 @@ func consumeBackupDataCollections(
 // writes the results metrics to the operation results.
 // later stored in the manifest using createBackupModels.
 func (op *BackupOperation) persistResults(
+	ctx context.Context,
 	started time.Time,
 	opStats *backupStats,
 ) error {
 	op.Results.StartedAt = started
 	op.Results.CompletedAt = time.Now()
 
+	// a pause stops new collection work the same way cancellation does
+	// (workCtx is cancelled, not ctx itself), so it's recorded the same way:
+	// a resumable, non-Failed stop rather than an outright Completed run.
+	cancelled := ctx.Err() != nil || op.isPaused()
+
 	op.Status = Completed
+	if cancelled {
+		op.Status = Cancelled
+	}
+
 	if !opStats.started {
-		op.Status = Failed
+		op.Results.ReadErrors = opStats.readErr
+		op.Results.WriteErrors = opStats.writeErr
+
+		if !cancelled {
+			op.Status = Failed
 
-		return multierror.Append(
-			errors.New("errors prevented the operation from processing"),
-			opStats.readErr,
-			opStats.writeErr)
+			return multierror.Append(
+				errors.New("errors prevented the operation from processing"),
+				opStats.readErr,
+				opStats.writeErr)
+		}
+
+		// cancelled or paused before Run reached its happy path: the
+		// checkpoint (stopped just above, by this same defer) may still hold
+		// a partial snapshot ID if kopia got partway through the upload
+		// before stopping, but Results itself has nothing further to record.
+		return nil
 	}
 
-	if opStats.readErr == nil && opStats.writeErr == nil && opStats.gc.Successful == 0 {
+	if !cancelled && opStats.readErr == nil && opStats.writeErr == nil && opStats.gc.Successful == 0 {
 		op.Status = NoData
 	}
 
@@ -229,6 +764,10 @@ func (op *BackupOperation) persistResults(
 	op.Results.ItemsWritten = opStats.k.TotalFileCount
 	op.Results.ResourceOwners = opStats.resourceCount
 
+	if dur := op.Results.CompletedAt.Sub(op.Results.StartedAt); dur > 0 {
+		op.Results.BytesPerSec = float64(op.Results.BytesUploaded) / dur.Seconds()
+	}
+
 	return nil
 }
 
@@ -250,16 +789,27 @@ func (op *BackupOperation) createBackupModels(
 	b := backup.New(
 		snapID, string(backupDetails.ModelStoreID), op.Status.String(),
 		op.Results.BackupID,
+		op.account.ID(),
 		op.Selectors,
 		op.Results.ReadWrites,
 		op.Results.StartAndEndTime,
 	)
+	b.ParentBackupID = op.Results.ParentBackupID
 
 	err = op.store.Put(ctx, model.BackupSchema, b)
 	if err != nil {
 		return errors.Wrap(err, "creating backup model")
 	}
 
+	// the backup model is durably written, so the checkpoint can no longer
+	// shadow it. Deleting is best-effort: ResumeBackupOperation rejects a
+	// leftover checkpoint whose backup model already exists.
+	if cr := op.getCheckpoint(); cr != nil {
+		if err := cr.delete(ctx); err != nil {
+			logger.Ctx(ctx).With("error", err).Error("deleting backup checkpoint")
+		}
+	}
+
 	op.bus.Event(
 		ctx,
 		events.BackupEnd,
@@ -0,0 +1,235 @@
+package operations
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/model"
+	"github.com/alcionai/corso/src/pkg/account"
+	"github.com/alcionai/corso/src/pkg/selectors"
+	"github.com/alcionai/corso/src/pkg/store"
+)
+
+// CheckpointMetadata is the persisted state of an in-progress backup.  Run()
+// writes one after connecting to M365, running the BeforeProduce hooks, and
+// loading any base backup, and keeps it current via a
+// CheckpointRunner so that a crash, cancellation, or network failure can be
+// resumed with ResumeBackupOperation instead of redoing completed work.
+type CheckpointMetadata struct {
+	model.BaseModel
+
+	BackupID      model.StableID         `json:"backupID"`
+	Selector      selectors.Selector     `json:"selector"`
+	SelectorsHash string                 `json:"selectorsHash"`
+	Service       string                 `json:"service"`
+	StartedAt     time.Time              `json:"startedAt"`
+	SnapshotID    string                 `json:"snapshotID"`
+	Collections   []CollectionCheckpoint `json:"collections"`
+
+	// BaseBackupID mirrors BackupOperation.baseBackupID, so a resumed run
+	// keeps anchoring its delta fetch on the same parent backup the
+	// interrupted attempt was using.
+	BaseBackupID model.StableID `json:"baseBackupID,omitempty"`
+
+	// RateLimitBytesPerSec, CollectionWorkers, and ItemFetchWorkers mirror
+	// the control.Options knobs the interrupted Run was constructed with, so
+	// a resumed run throttles and fans out the same way rather than reverting
+	// to the package defaults.
+	RateLimitBytesPerSec uint64 `json:"rateLimitBytesPerSec,omitempty"`
+	CollectionWorkers    int    `json:"collectionWorkers,omitempty"`
+	ItemFetchWorkers     int    `json:"itemFetchWorkers,omitempty"`
+
+	// CipherEnabled and CipherKeyID record whether the interrupted run was
+	// encrypting items, and with which master key. The data-encryption key
+	// itself is deliberately never written here -- it lives only in the
+	// process's memory and is zeroed on exit (see control.Sensitive.Zero) --
+	// so ResumeBackupOperation has just enough to refuse resuming an
+	// encrypted backup, rather than silently continuing unencrypted or with
+	// a second DEK mismatched against the first half of the same snapshot.
+	CipherEnabled bool   `json:"cipherEnabled,omitempty"`
+	CipherKeyID   string `json:"cipherKeyID,omitempty"`
+}
+
+// CollectionCheckpoint marks a single data.Collection as fully uploaded, keyed
+// by the resource owner and path it came from. On resume,
+// produceBackupDataCollections drops collections with these paths from what
+// the GraphConnector returns, so they aren't uploaded again; the connector
+// itself is not told about them and still enumerates them.
+type CollectionCheckpoint struct {
+	ResourceOwner  string    `json:"resourceOwner"`
+	CollectionPath string    `json:"collectionPath"`
+	CompletedAt    time.Time `json:"completedAt"`
+}
+
+// CheckpointRunner owns the checkpoint for a single backup and flushes it to
+// the model store on a fixed interval, plus whenever a collection completes.
+type CheckpointRunner struct {
+	store    *store.Wrapper
+	interval time.Duration
+
+	// mu guards meta as well as cancel/done below -- start() and stop() can
+	// run concurrently (e.g. BackupHandle.Pause racing Run's own startup), so
+	// both need the same protection the checkpointBox mutex gives the outer
+	// pointer.
+	mu   sync.Mutex
+	meta CheckpointMetadata
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newCheckpointRunner(sw *store.Wrapper, meta CheckpointMetadata) *CheckpointRunner {
+	// the checkpoint is looked up by BackupID (see ResumeBackupOperation and
+	// delete()), so it has to be stored under that same ID, the same way
+	// backup.New uses the caller-supplied BackupID as the backup model's ID.
+	meta.ID = model.StableID(meta.BackupID)
+
+	return &CheckpointRunner{
+		store:    sw,
+		interval: 30 * time.Second,
+		meta:     meta,
+	}
+}
+
+// start persists the initial checkpoint and begins the periodic flush loop.
+// The loop stops when ctx is cancelled or stop() is called.
+func (cr *CheckpointRunner) start(ctx context.Context) error {
+	if err := cr.flush(ctx); err != nil {
+		return errors.Wrap(err, "writing initial checkpoint")
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	cr.mu.Lock()
+	cr.cancel = cancel
+	cr.done = done
+	cr.mu.Unlock()
+
+	go cr.run(runCtx, done)
+
+	return nil
+}
+
+// run flushes the checkpoint on a fixed interval until ctx is cancelled. done
+// is the channel start() installed for this run, passed in rather than read
+// back off cr so the goroutine never has to take cr.mu itself.
+func (cr *CheckpointRunner) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(cr.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = cr.flush(ctx)
+		}
+	}
+}
+
+// snapshotID returns the kopia snapshot-in-progress ID recorded in the
+// checkpoint, if any. A resumed Run reuses it as the base snapshot so kopia
+// only has to persist what wasn't already flushed.
+func (cr *CheckpointRunner) snapshotID() string {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	return cr.meta.SnapshotID
+}
+
+// completedPaths returns the set of collection paths already recorded as
+// finished, keyed the same way recordCollection keys them. A resumed Run
+// uses this to skip re-fetching and re-uploading collections that already
+// landed in the prior attempt's snapshot.
+func (cr *CheckpointRunner) completedPaths() map[string]bool {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	done := make(map[string]bool, len(cr.meta.Collections))
+	for _, c := range cr.meta.Collections {
+		done[c.CollectionPath] = true
+	}
+
+	return done
+}
+
+// recordCollection appends a checkpoint entry for a collection that finished
+// uploading and immediately flushes it, so a crash right after doesn't lose
+// the record.
+func (cr *CheckpointRunner) recordCollection(ctx context.Context, resourceOwner, collectionPath string) error {
+	cr.mu.Lock()
+	cr.meta.Collections = append(cr.meta.Collections, CollectionCheckpoint{
+		ResourceOwner:  resourceOwner,
+		CollectionPath: collectionPath,
+		CompletedAt:    time.Now(),
+	})
+	cr.mu.Unlock()
+
+	return cr.flush(ctx)
+}
+
+// setSnapshotID records the kopia snapshot-in-progress ID once it's known.
+func (cr *CheckpointRunner) setSnapshotID(ctx context.Context, snapID string) error {
+	cr.mu.Lock()
+	cr.meta.SnapshotID = snapID
+	cr.mu.Unlock()
+
+	return cr.flush(ctx)
+}
+
+func (cr *CheckpointRunner) flush(ctx context.Context) error {
+	cr.mu.Lock()
+	meta := cr.meta
+	cr.mu.Unlock()
+
+	return cr.store.Put(ctx, model.CheckpointSchema, &meta)
+}
+
+// stop halts the background flush loop and persists one final checkpoint.
+// Safe to call concurrently with start() -- e.g. a Pause landing in the
+// window between setCheckpoint and start() in Run -- since cancel/done are
+// read under the same mutex start() writes them under.
+func (cr *CheckpointRunner) stop(ctx context.Context) error {
+	cr.mu.Lock()
+	cancel := cr.cancel
+	done := cr.done
+	cr.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+
+	return cr.flush(ctx)
+}
+
+// delete removes the checkpoint record. Callers must only do this once the
+// backup model itself has been durably written, so an incomplete checkpoint
+// never shadows a completed backup.
+func (cr *CheckpointRunner) delete(ctx context.Context) error {
+	cr.mu.Lock()
+	id := cr.meta.ID
+	cr.mu.Unlock()
+
+	return cr.store.Delete(ctx, model.CheckpointSchema, id)
+}
+
+// selectorsHash fingerprints the selector and account together so a resumed
+// backup can detect a selector or tenant change and refuse to reuse a stale
+// checkpoint. It has to cover the selector's full scope, not just its
+// service -- two selectors for the same tenant and service but different
+// resource owners or include/exclude sets are not the same backup, and must
+// not resume each other's checkpoint.
+func selectorsHash(sel selectors.Selector, acct account.Account) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%v|%v", sel, acct)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,71 @@
+package operations
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCheckpointRunner_CompletedPaths verifies that completedPaths reports
+// exactly the collections a prior attempt recorded, keyed the same way
+// produceBackupDataCollections looks them up when skipping already-uploaded
+// collections on resume.
+func TestCheckpointRunner_CompletedPaths(t *testing.T) {
+	cr := &CheckpointRunner{
+		meta: CheckpointMetadata{
+			Collections: []CollectionCheckpoint{
+				{ResourceOwner: "owner1", CollectionPath: "/mailboxes/owner1/inbox", CompletedAt: time.Now()},
+				{ResourceOwner: "owner1", CollectionPath: "/mailboxes/owner1/sent", CompletedAt: time.Now()},
+			},
+		},
+	}
+
+	done := cr.completedPaths()
+
+	if len(done) != 2 {
+		t.Fatalf("expected 2 completed paths, got %d", len(done))
+	}
+
+	if !done["/mailboxes/owner1/inbox"] {
+		t.Error("expected inbox path to be marked complete")
+	}
+
+	if !done["/mailboxes/owner1/sent"] {
+		t.Error("expected sent path to be marked complete")
+	}
+
+	if done["/mailboxes/owner1/drafts"] {
+		t.Error("did not expect drafts path to be marked complete")
+	}
+}
+
+// TestCheckpointRunner_SnapshotID verifies that a resumed Run can read back
+// the kopia snapshot-in-progress ID a prior attempt recorded.
+func TestCheckpointRunner_SnapshotID(t *testing.T) {
+	cr := &CheckpointRunner{
+		meta: CheckpointMetadata{SnapshotID: "snap-123"},
+	}
+
+	if got := cr.snapshotID(); got != "snap-123" {
+		t.Errorf("snapshotID() = %q, want %q", got, "snap-123")
+	}
+}
+
+// TestCheckpointBox_PauseCancelsWork verifies that pausing an operation
+// marks it paused and invokes whatever cancel func Run installed, without
+// requiring a call to have raced it into place first.
+func TestCheckpointBox_PauseCancelsWork(t *testing.T) {
+	op := &BackupOperation{checkpoint: &checkpointBox{}}
+
+	cancelled := false
+	op.setCancelWork(func() { cancelled = true })
+
+	op.pause()
+
+	if !op.isPaused() {
+		t.Error("expected isPaused() to be true after pause()")
+	}
+
+	if !cancelled {
+		t.Error("expected pause() to invoke the installed cancelWork func")
+	}
+}
@@ -0,0 +1,383 @@
+package operations
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/data"
+	"github.com/alcionai/corso/src/internal/model"
+	"github.com/alcionai/corso/src/pkg/control"
+	"github.com/alcionai/corso/src/pkg/store"
+)
+
+// dekSize is the length, in bytes, of a generated AES-256 data-encryption
+// key.
+const dekSize = 32
+
+// streamChunkSize bounds how much plaintext each AEAD-sealed chunk of an
+// encrypted item covers, so a single item's ciphertext can be streamed
+// instead of buffered in memory.
+const streamChunkSize = 64 * 1024
+
+// generateDataEncryptionKey creates a fresh, random per-backup key. It is
+// never persisted directly; wrapDataEncryptionKey wraps it with the cipher
+// config's master key first.
+func generateDataEncryptionKey() (control.Sensitive, error) {
+	dek := make([]byte, dekSize)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, errors.Wrap(err, "generating data encryption key")
+	}
+
+	return control.Sensitive(dek), nil
+}
+
+func newGCM(key control.Sensitive) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing AES cipher")
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing AEAD")
+	}
+
+	return gcm, nil
+}
+
+// wrapDataEncryptionKey encrypts the per-backup DEK with the cipher config's
+// master key, producing the bytes Run persists in the backup's
+// cipherMetadata record. BackupResults and the backup model don't carry
+// them.
+func wrapDataEncryptionKey(cc control.CipherConfig, dek control.Sensitive) ([]byte, error) {
+	gcm, err := newGCM(cc.MasterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "generating key-wrap nonce")
+	}
+
+	return gcm.Seal(nonce, nonce, dek, nil), nil
+}
+
+// unwrapDataEncryptionKey reverses wrapDataEncryptionKey during restore.
+func unwrapDataEncryptionKey(cc control.CipherConfig, wrapped []byte) (control.Sensitive, error) {
+	gcm, err := newGCM(cc.MasterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, errors.New("wrapped data encryption key is truncated")
+	}
+
+	nonce, ciphertext := wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():]
+
+	dek, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unwrapping data encryption key")
+	}
+
+	return control.Sensitive(dek), nil
+}
+
+// nonceRegistry is a nonces map guarded by a mutex so it can be written to
+// from every encryptedCollection's Items goroutine concurrently -- a real
+// concern once chunk0-4's CollectionWorkers runs several collections'
+// producers at once.
+type nonceRegistry struct {
+	mu     sync.Mutex
+	nonces map[string][]byte
+}
+
+func (r *nonceRegistry) set(itemID string, nonce []byte) {
+	r.mu.Lock()
+	r.nonces[itemID] = nonce
+	r.mu.Unlock()
+}
+
+// snapshot returns a copy of the accumulated nonces, safe to persist once all
+// collections have finished streaming.
+func (r *nonceRegistry) snapshot() map[string][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make(map[string][]byte, len(r.nonces))
+	for k, v := range r.nonces {
+		out[k] = v
+	}
+
+	return out
+}
+
+// encryptedCollection decorates a data.Collection so every item's reader is
+// AEAD-encrypted with the backup's data-encryption key before it reaches
+// kopia.
+type encryptedCollection struct {
+	data.Collection
+
+	dek    control.Sensitive
+	nonces *nonceRegistry
+}
+
+// encryptCollections wraps each collection in cs so its items are encrypted
+// with dek as they're read. The returned registry is filled in lazily as
+// items are streamed, possibly from several collections concurrently;
+// callers call snapshot() once consumeBackupDataCollections finishes and
+// persist the result into cipherMetadata.
+func encryptCollections(dek control.Sensitive, cs []data.Collection) ([]data.Collection, *nonceRegistry) {
+	nonces := &nonceRegistry{nonces: map[string][]byte{}}
+	out := make([]data.Collection, 0, len(cs))
+
+	for _, c := range cs {
+		out = append(out, &encryptedCollection{Collection: c, dek: dek, nonces: nonces})
+	}
+
+	return out, nonces
+}
+
+func (ec *encryptedCollection) Items(ctx context.Context) <-chan data.Stream {
+	in := ec.Collection.Items(ctx)
+	out := make(chan data.Stream)
+
+	go func() {
+		defer close(out)
+
+		for s := range in {
+			r, nonce, err := encryptReader(ec.dek, s.ToReader())
+			if err != nil {
+				// fail the item loudly instead of silently dropping it: a
+				// stream that errors on read causes kopia to surface a
+				// failure for this item, which opStats.writeErr picks up.
+				out <- erroringStream{Stream: s, err: errors.Wrap(err, "encrypting item")}
+				continue
+			}
+
+			ec.nonces.set(s.UUID(), nonce)
+
+			out <- encryptedStream{Stream: s, reader: r}
+		}
+	}()
+
+	return out
+}
+
+// encryptedStream overrides ToReader so item bytes are encrypted, leaving
+// every other data.Stream accessor (UUID, Deleted, ...) untouched.
+type encryptedStream struct {
+	data.Stream
+	reader io.ReadCloser
+}
+
+func (es encryptedStream) ToReader() io.ReadCloser {
+	return es.reader
+}
+
+// decryptedStream overrides ToReader so item bytes are decrypted, leaving
+// every other data.Stream accessor untouched. It's the restore counterpart
+// to encryptedStream.
+type decryptedStream struct {
+	data.Stream
+	reader io.ReadCloser
+}
+
+func (ds decryptedStream) ToReader() io.ReadCloser {
+	return ds.reader
+}
+
+// erroringStream is a data.Stream whose reader always fails with err. It
+// lets a collection decorator surface a per-item failure (a bad key, a
+// corrupt nonce) to kopia's own read path instead of silently omitting the
+// item from the backup or restore.
+type erroringStream struct {
+	data.Stream
+	err error
+}
+
+func (es erroringStream) ToReader() io.ReadCloser {
+	return io.NopCloser(&erroringReader{err: es.err})
+}
+
+type erroringReader struct {
+	err error
+}
+
+func (r *erroringReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// encryptReader wraps r so it yields length-prefixed, AES-GCM sealed chunks
+// of the plaintext instead of the plaintext itself. The nonce used for the
+// first chunk is returned so it can be recorded; every later chunk derives
+// its nonce by incrementing the previous one, so only one nonce needs
+// storing per item.
+func encryptReader(dek control.Sensitive, r io.ReadCloser) (io.ReadCloser, []byte, error) {
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, errors.Wrap(err, "generating stream nonce")
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		defer r.Close()
+
+		n := append([]byte(nil), nonce...)
+		buf := make([]byte, streamChunkSize)
+
+		for {
+			read, rerr := io.ReadFull(r, buf)
+			if read > 0 {
+				sealed := gcm.Seal(nil, n, buf[:read], nil)
+				incrementNonce(n)
+
+				if werr := writeChunk(pw, sealed); werr != nil {
+					pw.CloseWithError(werr)
+					return
+				}
+			}
+
+			if rerr == io.EOF || rerr == io.ErrUnexpectedEOF {
+				pw.Close()
+				return
+			}
+
+			if rerr != nil {
+				pw.CloseWithError(rerr)
+				return
+			}
+		}
+	}()
+
+	return pr, nonce, nil
+}
+
+// decryptReader reverses encryptReader during restore, given the nonce that
+// was recorded for this item at backup time.
+func decryptReader(dek control.Sensitive, nonce []byte, r io.ReadCloser) (io.ReadCloser, error) {
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		defer r.Close()
+
+		n := append([]byte(nil), nonce...)
+
+		for {
+			chunk, rerr := readChunk(r)
+			if len(chunk) > 0 {
+				opened, derr := gcm.Open(nil, n, chunk, nil)
+				if derr != nil {
+					pw.CloseWithError(errors.Wrap(derr, "decrypting chunk"))
+					return
+				}
+
+				incrementNonce(n)
+
+				if _, werr := pw.Write(opened); werr != nil {
+					pw.CloseWithError(werr)
+					return
+				}
+			}
+
+			if rerr == io.EOF {
+				pw.Close()
+				return
+			}
+
+			if rerr != nil {
+				pw.CloseWithError(rerr)
+				return
+			}
+		}
+	}()
+
+	return pr, nil
+}
+
+func writeChunk(w io.Writer, chunk []byte) error {
+	var lenBuf [4]byte
+	binary.BigEndian.PutUint32(lenBuf[:], uint32(len(chunk)))
+
+	if _, err := w.Write(lenBuf[:]); err != nil {
+		return err
+	}
+
+	_, err := w.Write(chunk)
+
+	return err
+}
+
+func readChunk(r io.Reader) ([]byte, error) {
+	var lenBuf [4]byte
+
+	if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+		return nil, err
+	}
+
+	chunk := make([]byte, binary.BigEndian.Uint32(lenBuf[:]))
+	if _, err := io.ReadFull(r, chunk); err != nil {
+		return nil, err
+	}
+
+	return chunk, nil
+}
+
+// cipherMetadata is the persisted record of a backup's wrapped
+// data-encryption key and the per-item nonces needed to decrypt it on
+// restore. It's stored separately from details.Details because it holds
+// key material and must never be merged into anything log-adjacent.
+type cipherMetadata struct {
+	model.BaseModel
+
+	BackupID   model.StableID    `json:"backupID"`
+	KeyID      string            `json:"keyID"`
+	WrappedDEK []byte            `json:"wrappedDEK"`
+	ItemNonces map[string][]byte `json:"itemNonces"`
+}
+
+func persistCipherMetadata(ctx context.Context, sw *store.Wrapper, cm cipherMetadata) error {
+	// the record is looked up by BackupID later (loadCipherMetadata), so it
+	// has to be stored under that same ID, the same way newCheckpointRunner
+	// uses the backup's own ID as the checkpoint model's ID.
+	cm.ID = model.StableID(cm.BackupID)
+
+	return sw.Put(ctx, model.CipherMetadataSchema, &cm)
+}
+
+// loadCipherMetadata retrieves the wrapped DEK and nonces for backupID so
+// restore can unwrap the key and decrypt each item's stream.
+func loadCipherMetadata(ctx context.Context, sw *store.Wrapper, backupID model.StableID) (cipherMetadata, error) {
+	var cm cipherMetadata
+	err := sw.Get(ctx, model.CipherMetadataSchema, backupID, &cm)
+
+	return cm, err
+}
+
+func incrementNonce(nonce []byte) {
+	for i := len(nonce) - 1; i >= 0; i-- {
+		nonce[i]++
+		if nonce[i] != 0 {
+			return
+		}
+	}
+}
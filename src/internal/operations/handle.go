@@ -0,0 +1,99 @@
+package operations
+
+import (
+	"context"
+	"time"
+)
+
+// BackupHandle is a live handle onto a backup started with
+// StartBackupOperation. It gives a caller a way to stop or pause a backup
+// without blocking on Run for the duration of the operation.
+type BackupHandle struct {
+	op     *BackupOperation
+	cancel context.CancelFunc
+
+	done chan struct{}
+	err  error
+}
+
+// StartBackupOperation runs op.Run in a background goroutine and returns a
+// BackupHandle for controlling it. The context passed to Run is derived from
+// ctx via an operation-owned cancel, so Cancel can stop the backup without
+// the caller needing to manage cancellation of its own context.
+func StartBackupOperation(ctx context.Context, op BackupOperation) *BackupHandle {
+	runCtx, cancel := context.WithCancel(ctx)
+
+	h := &BackupHandle{
+		op:     &op,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(h.done)
+		h.err = h.op.Run(runCtx)
+	}()
+
+	return h
+}
+
+// Cancel stops the backup. Run's deferred cleanup still executes: it marks
+// the operation Cancelled and records any snapshot-in-progress ID kopia
+// reported on the checkpoint. A backup model is written only if the upload
+// to kopia had already finished; a cancellation mid-upload leaves no backup
+// model, only the checkpoint.
+func (h *BackupHandle) Cancel() {
+	h.cancel()
+}
+
+// Pause stops new collection work -- no further collections are fetched or
+// handed to kopia -- and flushes the current checkpoint, so a later
+// ResumeBackupOperation can pick up from where this one left off. Run ends
+// the same way a Cancel would (Status is set to Cancelled and no backup
+// model is written unless a snapshot had already landed), but unlike Cancel,
+// Pause only stops this operation's own collection work; it does not cancel
+// the context StartBackupOperation was called with.
+//
+// op.pause() runs unconditionally, before the checkpoint is even looked up:
+// Run doesn't install a checkpoint runner until it finishes connectToM365,
+// and M365 auth can be slow enough that a Pause lands before that happens.
+// isPaused() is still checked inside Run once its own cancelWork func is
+// installed, so marking the operation paused here is never lost even though
+// there's nothing to flush yet.
+func (h *BackupHandle) Pause() error {
+	h.op.pause()
+
+	cr := h.op.getCheckpoint()
+	if cr == nil {
+		// nothing to flush yet -- op.pause() above is enough for Run to pick
+		// up once it reaches a point with a checkpoint to stop.
+		return nil
+	}
+
+	return cr.stop(detach(context.Background()))
+}
+
+// Wait blocks until the backup finishes, by completion, cancellation, or
+// error, and returns the result of Run.
+func (h *BackupHandle) Wait() error {
+	<-h.done
+	return h.err
+}
+
+// detachedContext carries ctx's values but never reports a deadline,
+// cancellation, or done signal of its own. Run's cleanup defer uses it so
+// that persisting final results and deleting the checkpoint still complete
+// after the caller has cancelled the operation's context.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// detach strips cancellation and deadline from ctx while preserving its
+// values (trace spans, logger, etc.).
+func detach(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
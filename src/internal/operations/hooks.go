@@ -0,0 +1,148 @@
+package operations
+
+import (
+	"context"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/data"
+	"github.com/alcionai/corso/src/internal/events"
+	"github.com/alcionai/corso/src/internal/kopia"
+	"github.com/alcionai/corso/src/pkg/selectors"
+)
+
+// BackupHook lets an integrator run arbitrary code around a backup's
+// lifecycle -- e.g. flush a mailbox rule change, notify a ticketing system,
+// snapshot a co-resident database, or quiesce a SharePoint site before it's
+// read. Each method corresponds to a phase boundary in Run.
+type BackupHook interface {
+	// BeforeProduce runs just before Run asks the GraphConnector for data to
+	// back up.
+	BeforeProduce(ctx context.Context, sel selectors.Selector) error
+	// AfterProduce runs once the collections to back up are known, before
+	// they're handed to kopia.
+	AfterProduce(ctx context.Context, cs []data.Collection) error
+	// BeforeConsume runs just before Run hands collections to kopia.
+	BeforeConsume(ctx context.Context) error
+	// AfterConsume runs as Run exits, whether or not kopia was reached or
+	// succeeded. It doesn't run if Run fails to connect to M365.
+	AfterConsume(ctx context.Context, stats *kopia.BackupStats) error
+	// OnError runs if any phase of Run after connecting to M365, including
+	// another hook, fails. A failed connection to M365 doesn't trigger it.
+	OnError(ctx context.Context, err error)
+}
+
+// WithHooks registers hooks to run around the backup lifecycle. Hooks run in
+// the order given for Before*/After* phases.
+func WithHooks(hooks ...BackupHook) Option {
+	return func(op *BackupOperation) {
+		op.hooks = append(op.hooks, hooks...)
+	}
+}
+
+// runBeforeProduce runs every registered hook's BeforeProduce in order,
+// stopping at the first error.
+func (op *BackupOperation) runBeforeProduce(ctx context.Context) error {
+	return op.runHookPhase(ctx, "BeforeProduce", func(ctx context.Context, h BackupHook) error {
+		return h.BeforeProduce(ctx, op.Selectors)
+	})
+}
+
+// runAfterProduce runs every registered hook's AfterProduce in order,
+// stopping at the first error.
+func (op *BackupOperation) runAfterProduce(ctx context.Context, cs []data.Collection) error {
+	return op.runHookPhase(ctx, "AfterProduce", func(ctx context.Context, h BackupHook) error {
+		return h.AfterProduce(ctx, cs)
+	})
+}
+
+// runBeforeConsume runs every registered hook's BeforeConsume in order,
+// stopping at the first error.
+func (op *BackupOperation) runBeforeConsume(ctx context.Context) error {
+	return op.runHookPhase(ctx, "BeforeConsume", func(ctx context.Context, h BackupHook) error {
+		return h.BeforeConsume(ctx)
+	})
+}
+
+// runAfterConsume runs every registered hook's AfterConsume, even when the
+// backup failed, so integrators can reliably undo whatever
+// BeforeProduce/BeforeConsume did (e.g. restart containers that were
+// stopped for the backup). Unlike the other phases, it doesn't stop at the
+// first error: hook 2's cleanup (e.g. un-quiescing a database) must run
+// whether or not hook 1's cleanup succeeded, so every error is collected and
+// returned together instead of aborting the loop.
+func (op *BackupOperation) runAfterConsume(ctx context.Context, stats *kopia.BackupStats) error {
+	var result *multierror.Error
+
+	for i, h := range op.hooks {
+		op.bus.Event(
+			ctx,
+			events.HookStart,
+			map[string]any{
+				events.BackupID: op.Results.BackupID,
+				events.HookName: "AfterConsume",
+			},
+		)
+
+		err := h.AfterConsume(ctx, stats)
+
+		op.bus.Event(
+			ctx,
+			events.HookEnd,
+			map[string]any{
+				events.BackupID: op.Results.BackupID,
+				events.HookName: "AfterConsume",
+			},
+		)
+
+		if err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "running AfterConsume hook %d", i))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// runOnError notifies every registered hook that the backup failed. Errors
+// returned by OnError itself are not possible (OnError has no return value)
+// so this never aborts anything; it's pure observability.
+func (op *BackupOperation) runOnError(ctx context.Context, runErr error) {
+	for _, h := range op.hooks {
+		h.OnError(ctx, runErr)
+	}
+}
+
+func (op *BackupOperation) runHookPhase(
+	ctx context.Context,
+	phase string,
+	run func(ctx context.Context, h BackupHook) error,
+) error {
+	for i, h := range op.hooks {
+		op.bus.Event(
+			ctx,
+			events.HookStart,
+			map[string]any{
+				events.BackupID: op.Results.BackupID,
+				events.HookName: phase,
+			},
+		)
+
+		err := run(ctx, h)
+
+		op.bus.Event(
+			ctx,
+			events.HookEnd,
+			map[string]any{
+				events.BackupID: op.Results.BackupID,
+				events.HookName: phase,
+			},
+		)
+
+		if err != nil {
+			return errors.Wrapf(err, "running %s hook %d", phase, i)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package operations
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/connector"
+	"github.com/alcionai/corso/src/internal/events"
+	"github.com/alcionai/corso/src/internal/model"
+	"github.com/alcionai/corso/src/pkg/backup"
+	"github.com/alcionai/corso/src/pkg/backup/details"
+	"github.com/alcionai/corso/src/pkg/logger"
+	"github.com/alcionai/corso/src/pkg/store"
+)
+
+// baseBackup bundles a prior backup's model and details, loaded once at the
+// start of Run so the rest of the incremental path can reuse its snapshot
+// and feed its details forward to produceBackupDataCollections.
+type baseBackup struct {
+	model *backup.Backup
+	deets *details.Details
+}
+
+// loadBaseBackup fetches the prior backup this operation is anchored on and
+// confirms it's a valid parent: same tenant, same service, same selector
+// scope. A mismatch on any of those means the delta the GraphConnector would
+// issue wouldn't mean what the caller expects, so it's treated as an error
+// rather than silently falling back.
+func loadBaseBackup(
+	ctx context.Context,
+	sw *store.Wrapper,
+	op BackupOperation,
+) (*baseBackup, error) {
+	bup := &backup.Backup{}
+	if err := sw.Get(ctx, model.BackupSchema, op.baseBackupID, bup); err != nil {
+		return nil, errors.Wrap(err, "loading base backup")
+	}
+
+	if bup.TenantID != op.account.ID() {
+		return nil, errors.New("base backup belongs to a different tenant")
+	}
+
+	if bup.Selectors.Service != op.Selectors.Service {
+		return nil, errors.New("base backup service does not match")
+	}
+
+	if bup.Selectors.DiscreteOwner != op.Selectors.DiscreteOwner {
+		return nil, errors.New("base backup selector scope does not match")
+	}
+
+	deets := &details.Details{}
+	if err := sw.Get(ctx, model.BackupDetailsSchema, model.StableID(bup.DetailsID), &deets.DetailsModel); err != nil {
+		return nil, errors.Wrap(err, "loading base backup details")
+	}
+
+	return &baseBackup{model: bup, deets: deets}, nil
+}
+
+// isDeltaExpiredErr reports whether err is the GraphConnector's signal that
+// an incremental fetch's delta token (mail/contacts/events delta token, or a
+// OneDrive delta link) has gone stale upstream -- Graph returns a 410 Gone
+// for those once they age out. Run treats this as a retryable condition, not
+// a hard failure: it falls back to a full enumeration instead of failing the
+// whole backup over a token that's expected to expire occasionally.
+func isDeltaExpiredErr(err error) bool {
+	return errors.Is(err, connector.ErrDeltaExpired)
+}
+
+// fallbackToFullBackup drops this operation's incremental anchor and emits
+// events.BackupFallbackFull with reason as the human-readable cause. Run
+// calls this both when the parent backup itself turns out to be unusable
+// (missing, or mismatched tenant/service/selector) and when the parent's
+// delta token expires partway through producing collections.
+func (op *BackupOperation) fallbackToFullBackup(ctx context.Context, reason error) {
+	logger.Ctx(ctx).With("error", reason).Info("falling back to full backup")
+
+	op.bus.Event(
+		ctx,
+		events.BackupFallbackFull,
+		map[string]any{
+			events.BackupID: op.Results.BackupID,
+			events.Reason:   reason.Error(),
+		},
+	)
+
+	op.baseBackupID = ""
+	op.Results.ParentBackupID = ""
+}
@@ -0,0 +1,61 @@
+package operations
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/alcionai/corso/src/internal/data"
+)
+
+// itemTally counts items passing through a backup's collections, split by
+// whether each item is a tombstone (data.Stream.Deleted()) or live data. Run
+// uses the totals to fill in BackupResults.ItemsDeleted and, since the
+// connector doesn't surface an added/modified split for a delta fetch, the
+// best-effort approximation for ItemsAdded/ItemsModified documented on those
+// fields.
+type itemTally struct {
+	deleted int64
+	live    int64
+}
+
+func (t *itemTally) add(s data.Stream) {
+	if s.Deleted() {
+		atomic.AddInt64(&t.deleted, 1)
+	} else {
+		atomic.AddInt64(&t.live, 1)
+	}
+}
+
+// tallyCollections decorates each collection in cs so its items are counted
+// as they're streamed, without altering the items themselves.
+func tallyCollections(tally *itemTally, cs []data.Collection) []data.Collection {
+	out := make([]data.Collection, 0, len(cs))
+
+	for _, c := range cs {
+		out = append(out, &tallyCollection{Collection: c, tally: tally})
+	}
+
+	return out
+}
+
+type tallyCollection struct {
+	data.Collection
+
+	tally *itemTally
+}
+
+func (tc *tallyCollection) Items(ctx context.Context) <-chan data.Stream {
+	in := tc.Collection.Items(ctx)
+	out := make(chan data.Stream)
+
+	go func() {
+		defer close(out)
+
+		for s := range in {
+			tc.tally.add(s)
+			out <- s
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,78 @@
+package operations
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/events"
+	"github.com/alcionai/corso/src/internal/kopia"
+	"github.com/alcionai/corso/src/pkg/control"
+	"github.com/alcionai/corso/src/pkg/store"
+)
+
+// Status is the terminal state of an operation's Run call.
+type Status int
+
+const (
+	// NoData means Run completed without anything to back up or restore.
+	NoData Status = iota
+	// Completed means Run finished successfully.
+	Completed
+	// Failed means Run hit an unrecoverable error.
+	Failed
+	// Cancelled means Run stopped early via Cancel or Pause. Unlike Failed,
+	// a Cancelled run is resumable: any checkpoint it left behind is still
+	// valid for ResumeBackupOperation.
+	Cancelled
+)
+
+func (s Status) String() string {
+	switch s {
+	case NoData:
+		return "NoData"
+	case Completed:
+		return "Completed"
+	case Failed:
+		return "Failed"
+	case Cancelled:
+		return "Cancelled"
+	default:
+		return "Unknown"
+	}
+}
+
+// operation is embedded by every concrete operation type (BackupOperation,
+// ...) so they share the same construction, validation, and result-status
+// plumbing.
+type operation struct {
+	Status Status
+
+	options control.Options
+	bus     events.Eventer
+	kopia   *kopia.Wrapper
+	store   *store.Wrapper
+}
+
+func newOperation(opts control.Options, bus events.Eventer, kw *kopia.Wrapper, sw *store.Wrapper) operation {
+	return operation{
+		options: opts,
+		bus:     bus,
+		kopia:   kw,
+		store:   sw,
+	}
+}
+
+func (op operation) validate() error {
+	if op.bus == nil {
+		return errors.New("no event bus configured")
+	}
+
+	if op.kopia == nil {
+		return errors.New("no kopia wrapper configured")
+	}
+
+	if op.store == nil {
+		return errors.New("no model store configured")
+	}
+
+	return nil
+}
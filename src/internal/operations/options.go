@@ -0,0 +1,20 @@
+package operations
+
+import (
+	"github.com/alcionai/corso/src/internal/model"
+)
+
+// Option configures a BackupOperation at construction time. Options are
+// applied in order after the required constructor args are set, so a later
+// option can see and override an earlier one.
+type Option func(*BackupOperation)
+
+// WithBaseBackup anchors the new operation on a prior, completed backup.
+// Run reuses that backup's kopia snapshot as the parent for
+// kw.BackupCollections and asks the GraphConnector for a delta scoped to
+// items changed since it, instead of enumerating everything from scratch.
+func WithBaseBackup(id model.StableID) Option {
+	return func(op *BackupOperation) {
+		op.baseBackupID = id
+	}
+}
@@ -0,0 +1,165 @@
+package operations
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/alcionai/corso/src/internal/data"
+	"github.com/alcionai/corso/src/internal/events"
+	"github.com/alcionai/corso/src/internal/model"
+)
+
+// progressReportInterval controls how often Run emits events.BackupProgress
+// while a rate-limited backup is in flight.
+const progressReportInterval = 10 * time.Second
+
+// throughputTracker accumulates bytes read across every collection so Run
+// can report an aggregate bytes/sec figure without each collection knowing
+// about the others.
+type throughputTracker struct {
+	bytes int64
+}
+
+func (t *throughputTracker) add(n int) {
+	atomic.AddInt64(&t.bytes, int64(n))
+}
+
+// reset returns the byte count accumulated since the last reset and zeroes
+// the counter.
+func (t *throughputTracker) reset() int64 {
+	return atomic.SwapInt64(&t.bytes, 0)
+}
+
+// reportProgress emits events.BackupProgress with the observed bytes/sec
+// once per progressReportInterval until ctx is cancelled.
+func reportProgress(
+	ctx context.Context,
+	bus events.Eventer,
+	backupID model.StableID,
+	track *throughputTracker,
+) {
+	ticker := time.NewTicker(progressReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			bytes := track.reset()
+
+			bus.Event(
+				ctx,
+				events.BackupProgress,
+				map[string]any{
+					events.BackupID:    backupID,
+					events.BytesPerSec: float64(bytes) / progressReportInterval.Seconds(),
+				},
+			)
+		}
+	}
+}
+
+// rateLimitedCollection decorates a data.Collection so every item's reader is
+// throttled through a shared token-bucket limiter, keeping the aggregate
+// upload rate across all collections under RateLimitBytesPerSec.
+type rateLimitedCollection struct {
+	data.Collection
+
+	limiter *rate.Limiter
+	track   *throughputTracker
+}
+
+// rateLimitCollections wraps each collection in cs so reads from any of them
+// draw from the same limiter.
+func rateLimitCollections(limiter *rate.Limiter, track *throughputTracker, cs []data.Collection) []data.Collection {
+	out := make([]data.Collection, 0, len(cs))
+
+	for _, c := range cs {
+		out = append(out, &rateLimitedCollection{Collection: c, limiter: limiter, track: track})
+	}
+
+	return out
+}
+
+func (rc *rateLimitedCollection) Items(ctx context.Context) <-chan data.Stream {
+	in := rc.Collection.Items(ctx)
+	out := make(chan data.Stream)
+
+	go func() {
+		defer close(out)
+
+		for s := range in {
+			out <- rateLimitedStream{Stream: s, ctx: ctx, limiter: rc.limiter, track: rc.track}
+		}
+	}()
+
+	return out
+}
+
+// rateLimitedStream overrides ToReader so item bytes are throttled, leaving
+// every other data.Stream accessor untouched.
+type rateLimitedStream struct {
+	data.Stream
+
+	ctx     context.Context
+	limiter *rate.Limiter
+	track   *throughputTracker
+}
+
+func (rs rateLimitedStream) ToReader() io.ReadCloser {
+	return &rateLimitedReader{
+		ReadCloser: rs.Stream.ToReader(),
+		ctx:        rs.ctx,
+		limiter:    rs.limiter,
+		track:      rs.track,
+	}
+}
+
+type rateLimitedReader struct {
+	io.ReadCloser
+
+	ctx     context.Context
+	limiter *rate.Limiter
+	track   *throughputTracker
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		if werr := waitN(r.ctx, r.limiter, n); werr != nil {
+			return n, werr
+		}
+
+		r.track.add(n)
+	}
+
+	return n, err
+}
+
+// waitN throttles n bytes through limiter, split into chunks no larger than
+// the limiter's burst -- WaitN itself rejects any single request bigger than
+// that outright rather than blocking for it, and an ordinary read-buffer
+// size routinely exceeds a modest configured rate.
+func waitN(ctx context.Context, limiter *rate.Limiter, n int) error {
+	burst := limiter.Burst()
+
+	for n > 0 {
+		chunk := n
+		if chunk > burst {
+			chunk = burst
+		}
+
+		if err := limiter.WaitN(ctx, chunk); err != nil {
+			return err
+		}
+
+		n -= chunk
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package operations
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/data"
+	"github.com/alcionai/corso/src/internal/model"
+	"github.com/alcionai/corso/src/pkg/control"
+	"github.com/alcionai/corso/src/pkg/store"
+)
+
+// DecryptRestoreCollections is the restore-side counterpart to
+// encryptCollections. A RestoreOperation calls it on the collections it
+// builds from a kopia snapshot so items encrypted by a prior, cipher-enabled
+// BackupOperation come back out as plaintext: it locates the wrapped DEK on
+// the backup's cipherMetadata, unwraps it with the master key, and decorates
+// every collection so its items decrypt as they're streamed.
+func DecryptRestoreCollections(
+	ctx context.Context,
+	sw *store.Wrapper,
+	cc control.CipherConfig,
+	backupID model.StableID,
+	cs []data.Collection,
+) ([]data.Collection, error) {
+	if !cc.Enabled {
+		return cs, nil
+	}
+
+	cm, err := loadCipherMetadata(ctx, sw, backupID)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading cipher metadata")
+	}
+
+	dek, err := unwrapDataEncryptionKey(cc, cm.WrappedDEK)
+	if err != nil {
+		return nil, errors.Wrap(err, "unwrapping data encryption key")
+	}
+
+	out := make([]data.Collection, 0, len(cs))
+
+	for _, c := range cs {
+		out = append(out, &decryptedCollection{Collection: c, dek: dek, nonces: cm.ItemNonces})
+	}
+
+	return out, nil
+}
+
+// decryptedCollection decorates a data.Collection so every item's reader is
+// decrypted with the backup's data-encryption key before the restore writes
+// it back out.
+type decryptedCollection struct {
+	data.Collection
+
+	dek    control.Sensitive
+	nonces map[string][]byte
+}
+
+func (dc *decryptedCollection) Items(ctx context.Context) <-chan data.Stream {
+	in := dc.Collection.Items(ctx)
+	out := make(chan data.Stream)
+
+	go func() {
+		defer close(out)
+
+		for s := range in {
+			nonce, ok := dc.nonces[s.UUID()]
+			if !ok {
+				out <- erroringStream{Stream: s, err: errors.Errorf("no recorded nonce for item %s", s.UUID())}
+				continue
+			}
+
+			r, err := decryptReader(dc.dek, nonce, s.ToReader())
+			if err != nil {
+				out <- erroringStream{Stream: s, err: errors.Wrap(err, "decrypting item")}
+				continue
+			}
+
+			out <- decryptedStream{Stream: s, reader: r}
+		}
+	}()
+
+	return out
+}
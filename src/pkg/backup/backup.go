@@ -0,0 +1,52 @@
+package backup
+
+import (
+	"github.com/alcionai/corso/src/internal/model"
+	"github.com/alcionai/corso/src/internal/stats"
+	"github.com/alcionai/corso/src/pkg/selectors"
+)
+
+// Backup is the durable record of a single backup run: what was backed up,
+// where its snapshot and details live, and how it went.
+type Backup struct {
+	model.BaseModel
+
+	SnapshotID string             `json:"snapshotID"`
+	DetailsID  string             `json:"detailsID"`
+	Status     string             `json:"status"`
+	Selectors  selectors.Selector `json:"selectors"`
+
+	// TenantID anchors this backup to the M365 tenant it was taken against,
+	// so an incremental backup can refuse to anchor on another tenant's
+	// snapshot/delta token.
+	TenantID string `json:"tenantID"`
+
+	// ParentBackupID is empty for a full backup and set to the anchoring
+	// backup's ID for an incremental one.
+	ParentBackupID model.StableID `json:"parentBackupID,omitempty"`
+
+	stats.ReadWrites
+	stats.StartAndEndTime
+}
+
+// New constructs a Backup, storing it under backupID the same way every
+// other model keys itself by its own StableID.
+func New(
+	snapshotID, detailsID, status string,
+	backupID model.StableID,
+	tenantID string,
+	sel selectors.Selector,
+	rw stats.ReadWrites,
+	tt stats.StartAndEndTime,
+) *Backup {
+	return &Backup{
+		BaseModel:       model.BaseModel{ID: backupID},
+		SnapshotID:      snapshotID,
+		DetailsID:       detailsID,
+		Status:          status,
+		TenantID:        tenantID,
+		Selectors:       sel,
+		ReadWrites:      rw,
+		StartAndEndTime: tt,
+	}
+}
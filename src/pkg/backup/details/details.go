@@ -0,0 +1,19 @@
+package details
+
+import "github.com/alcionai/corso/src/internal/model"
+
+// DetailsModel is the persisted, per-item manifest for a single backup.
+type DetailsModel struct {
+	model.BaseModel
+
+	// ModelStoreID is the store's own key for this record, recorded on the
+	// owning Backup so a later read can fetch it directly instead of
+	// re-deriving it.
+	ModelStoreID string `json:"modelStoreID"`
+}
+
+// Details wraps a DetailsModel with whatever in-memory state a backup or
+// restore operation needs while it's being built, before it's persisted.
+type Details struct {
+	DetailsModel
+}
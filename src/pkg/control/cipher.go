@@ -0,0 +1,41 @@
+package control
+
+// Sensitive wraps secret material so that it can be carried around in
+// structs and logged without ever printing the underlying bytes. Modeled on
+// dgraph's x.Sensitive.
+type Sensitive []byte
+
+// String implements fmt.Stringer with a fixed redaction so that %v/%s
+// formatting of a Sensitive, or of a struct holding one in an exported field
+// (such as CipherConfig.MasterKey), prints the redaction instead of the
+// bytes. fmt doesn't call String on unexported struct fields, so a struct
+// holding a Sensitive in an unexported field still prints the raw bytes.
+func (s Sensitive) String() string {
+	return "***"
+}
+
+// Zero overwrites the underlying bytes in place. Callers should zero a
+// CipherConfig's MasterKey once the operation that needed it has finished
+// with it, instead of waiting on the garbage collector.
+func (s Sensitive) Zero() {
+	for i := range s {
+		s[i] = 0
+	}
+}
+
+// CipherConfig configures client-side envelope encryption for a backup, so
+// that item bytes are encrypted before kopia ever sees them. This makes
+// backups zero-knowledge to an operator holding only the kopia repo
+// password.
+type CipherConfig struct {
+	// Enabled turns envelope encryption on. When false every other field is
+	// ignored and data.Collections pass through to kopia unmodified.
+	Enabled bool
+	// MasterKey wraps each backup's random data-encryption key. Callers load
+	// it from env, file, or KMS; it is never logged (see Sensitive) and
+	// should be zeroed once the operation completes.
+	MasterKey Sensitive
+	// KeyID identifies which master key wrapped a backup's DEK, so a future
+	// restore knows which key to ask for without guessing.
+	KeyID string
+}
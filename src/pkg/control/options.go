@@ -0,0 +1,19 @@
+package control
+
+// Options configures the knobs a caller can set on an operation (backup or
+// restore) that aren't specific to any one service or resource.
+type Options struct {
+	// Cipher configures client-side envelope encryption for a backup. The
+	// zero value (Enabled: false) leaves data.Collections untouched.
+	Cipher CipherConfig
+
+	// RateLimitBytesPerSec caps the aggregate read rate across every
+	// collection in a backup. Zero means unlimited.
+	RateLimitBytesPerSec uint64
+	// CollectionWorkers bounds how many collections the connector fetches
+	// concurrently. Zero means the connector's default.
+	CollectionWorkers int
+	// ItemFetchWorkers bounds how many items within a collection the
+	// connector fetches concurrently. Zero means the connector's default.
+	ItemFetchWorkers int
+}